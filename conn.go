@@ -0,0 +1,76 @@
+package finish
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// LimitListener returns a net.Listener which wraps l so that Accept blocks
+// once max connections accepted through it are simultaneously in flight,
+// providing basic backpressure without requiring application code to
+// instrument http.Server.ConnState itself.
+func LimitListener(l net.Listener, max int) net.Listener {
+	return &limitListener{
+		Listener: l,
+		sem:      make(chan struct{}, max),
+	}
+}
+
+type limitListener struct {
+	net.Listener
+	sem chan struct{}
+}
+
+func (l *limitListener) Accept() (net.Conn, error) {
+	l.sem <- struct{}{}
+
+	c, err := l.Listener.Accept()
+	if err != nil {
+		<-l.sem
+		return nil, err
+	}
+
+	return &releaseConn{Conn: c, release: func() { <-l.sem }}, nil
+}
+
+// CountingListener returns a net.Listener which wraps l, and a function
+// reporting how many connections accepted through it are currently open.
+// This gives a live in-flight-connection count without requiring
+// application code to instrument http.Server.ConnState itself.
+func CountingListener(l net.Listener) (net.Listener, func() int) {
+	cl := &countingListener{Listener: l}
+	return cl, func() int {
+		return int(atomic.LoadInt64(&cl.count))
+	}
+}
+
+type countingListener struct {
+	net.Listener
+	count int64
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	atomic.AddInt64(&l.count, 1)
+
+	return &releaseConn{Conn: c, release: func() { atomic.AddInt64(&l.count, -1) }}, nil
+}
+
+// releaseConn wraps a net.Conn so release is called exactly once, whenever
+// the connection is closed, no matter how many times Close is called.
+type releaseConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *releaseConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.release)
+	return err
+}
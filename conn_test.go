@@ -0,0 +1,131 @@
+package finish
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func dial(t *testing.T, addr string) net.Conn {
+	t.Helper()
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	return conn
+}
+
+func TestLimitListener(t *testing.T) {
+	raw, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer raw.Close()
+
+	l := LimitListener(raw, 1)
+
+	accepted := make(chan net.Conn, 2)
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- c
+		}
+	}()
+
+	clientConn1 := dial(t, raw.Addr().String())
+	defer clientConn1.Close()
+
+	var serverConn1 net.Conn
+	select {
+	case serverConn1 = <-accepted:
+	case <-time.After(time.Second):
+		t.Fatal("expected first connection to be accepted")
+	}
+
+	clientConn2 := dial(t, raw.Addr().String())
+	defer clientConn2.Close()
+
+	select {
+	case <-accepted:
+		t.Fatal("expected second connection to be blocked while first is open")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// closing the server-accepted connection, not the client dial, is what
+	// releases the limitListener's semaphore slot
+	serverConn1.Close()
+
+	select {
+	case <-accepted:
+	case <-time.After(time.Second):
+		t.Fatal("expected second connection to be accepted after first closed")
+	}
+}
+
+func TestCountingListener(t *testing.T) {
+	raw, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer raw.Close()
+
+	l, count := CountingListener(raw)
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			return
+		}
+		accepted <- c
+	}()
+
+	conn := dial(t, raw.Addr().String())
+	defer conn.Close()
+
+	var serverConn net.Conn
+	select {
+	case serverConn = <-accepted:
+	case <-time.After(time.Second):
+		t.Fatal("expected connection to be accepted")
+	}
+
+	if got := count(); got != 1 {
+		t.Errorf("expected count to be 1, got %d", got)
+	}
+
+	serverConn.Close()
+
+	// give the Close() bookkeeping a moment to run
+	time.Sleep(100 * time.Millisecond)
+
+	if got := count(); got != 0 {
+		t.Errorf("expected count to be 0 after close, got %d", got)
+	}
+}
+
+func TestWithConnCount(t *testing.T) {
+	srv := &testServer{}
+	log := &logRecorder{}
+
+	fin := New()
+	fin.Log = log
+	fin.Add(srv, WithConnCount(func() int { return 34 }))
+
+	go fin.Trigger()
+
+	fin.Wait()
+
+	found := false
+	for _, info := range log.infos {
+		if info == "finish: shutting down server (34 active connections) ..." {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected active connection count in log output, got: %v", log.infos)
+	}
+}
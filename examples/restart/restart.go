@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/pseidemann/finish"
+)
+
+func main() {
+	http.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Second)
+		fmt.Fprintln(w, "world")
+	})
+
+	ln, err := finish.Listen("tcp", "localhost:8080")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	srv := &http.Server{Addr: "localhost:8080"}
+
+	fin := &finish.Finisher{RestartSignal: finish.DefaultRestartSignal}
+	fin.Add(srv)
+
+	go func() {
+		if err := srv.Serve(ln); err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	// tell a parent process, if any, that this process is ready to serve
+	if err := finish.Ready(); err != nil {
+		log.Fatal(err)
+	}
+
+	fin.Wait()
+}
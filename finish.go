@@ -3,9 +3,11 @@ package finish
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"sort"
 	"sync"
 	"syscall"
 	"time"
@@ -34,9 +36,13 @@ type Server interface {
 }
 
 type serverKeeper struct {
-	srv     Server
-	name    string
-	timeout time.Duration
+	srv          Server
+	name         string
+	timeout      time.Duration
+	preShutdown  func() error
+	postShutdown func()
+	connCount    func() int
+	order        int
 }
 
 // Finisher implements graceful shutdown of servers.
@@ -58,9 +64,50 @@ type Finisher struct {
 	// It defaults to DefaultSignals which contains SIGINT and SIGTERM.
 	Signals []os.Signal
 
-	mutex   sync.Mutex
-	keepers []*serverKeeper
-	manSig  chan interface{}
+	// RestartSignal enables zero-downtime restarts: when set, receiving this
+	// signal (or calling Restart()) makes finish re-exec the running binary
+	// instead of shutting down. The listeners created via Listen() are
+	// passed on to the new process, which is given up to RestartHammerTime
+	// to report readiness. Once it is ready, finish runs the normal
+	// drain-and-shutdown sequence on the current process' servers; if it
+	// fails to start or does not become ready in time, the restart is
+	// aborted and the current process keeps serving.
+	//
+	// RestartSignal is opt-in and nil by default. DefaultRestartSignal
+	// (SIGHUP) is provided as a convenient value to set it to.
+	RestartSignal os.Signal
+
+	// RestartHammerTime is the maximum amount of time to wait for the new
+	// process to report readiness during a restart before giving up on it
+	// and continuing to serve with the current process.
+	// It defaults to DefaultRestartHammerTime which is 10 seconds.
+	RestartHammerTime time.Duration
+
+	// Sequential makes Wait shut down the servers of each shutdown phase
+	// (see WithShutdownOrder) one after another, in the order they were
+	// added via Add(), instead of the default of shutting down the servers
+	// of a phase concurrently. Phases are drained in ascending order either way.
+	//
+	// The default concurrent shutdown means the time spent on a phase is
+	// bound by its slowest server's timeout rather than the sum of its
+	// servers' timeouts. Sequential trades that off for a predictable
+	// shutdown order.
+	Sequential bool
+
+	// PreShutdownDelay, if set, makes Wait sleep for this long after the
+	// shutdown signal is received and before the first server is shut down.
+	// This is useful to give a load balancer time to notice a failing
+	// readiness probe, flipped by a BeforeShutdown hook, before connections
+	// are cut.
+	PreShutdownDelay time.Duration
+
+	mutex          sync.Mutex
+	keepers        []*serverKeeper
+	manSig         chan interface{}
+	manRestartSig  chan interface{}
+	onSignal       []func(os.Signal)
+	beforeShutdown []func() error
+	afterShutdown  []func()
 }
 
 // New creates a Finisher. This is a convenience constructor if no changes to the default configuration are needed.
@@ -126,41 +173,200 @@ func (f *Finisher) Add(srv Server, opts ...Option) {
 }
 
 // Wait blocks until one of the shutdown signals is received and then closes all servers with a timeout.
-func (f *Finisher) Wait() {
+//
+// Servers are shut down phase by phase, in ascending WithShutdownOrder
+// order. By default, the servers of a phase are shut down concurrently, so
+// the time spent on a phase is bound by its slowest server's timeout. Set
+// Finisher.Sequential to shut them down one after another instead.
+//
+// If any server fails to shut down, the returned error wraps all such
+// errors, joined via errors.Join. A nil error means every server shut down
+// cleanly.
+//
+// If Finisher.RestartSignal is set, receiving that signal (or calling
+// Restart()) triggers a zero-downtime restart instead: Wait keeps blocking
+// until the new process is ready, after which it falls through to the
+// normal drain-and-shutdown sequence, or goes on waiting if the restart
+// failed.
+func (f *Finisher) Wait() error {
 	f.updateNames()
 
 	signals := f.signals()
 	stop := make(chan os.Signal, len(signals))
 	signal.Notify(stop, signals...)
 
-	// wait for signal
-	select {
-	case sig := <-stop:
-		if sig == syscall.SIGINT {
-			// fix prints after "^C"
-			fmt.Println("")
+	var restartStop chan os.Signal
+	if f.RestartSignal != nil {
+		restartStop = make(chan os.Signal, 1)
+		signal.Notify(restartStop, f.RestartSignal)
+	}
+
+	var sig os.Signal
+
+	for {
+		// wait for signal
+		select {
+		case sig = <-stop:
+			if sig == syscall.SIGINT {
+				// fix prints after "^C"
+				fmt.Println("")
+			}
+		case sig = <-restartStop:
+			f.log().Infof("finish: restart signal received")
+			if !f.doRestart() {
+				continue
+			}
+		case <-f.getManSig():
+			// Trigger() was called
+			sig = nil
+		case <-f.getManRestartSig():
+			f.log().Infof("finish: restart triggered")
+			if !f.doRestart() {
+				continue
+			}
+			sig = nil
 		}
-	case <-f.getManSig():
-		// Trigger() was called
+
+		break
+	}
+
+	for _, fn := range f.onSignal {
+		fn(sig)
 	}
 
 	f.log().Infof("finish: shutdown signal received")
 
-	for _, keeper := range f.keepers {
-		ctx, cancel := context.WithTimeout(context.Background(), keeper.timeout)
-		defer cancel()
+	for _, fn := range f.beforeShutdown {
+		if err := fn(); err != nil {
+			f.log().Errorf("finish: before-shutdown hook failed: %s", err)
+		}
+	}
+
+	if f.PreShutdownDelay > 0 {
+		f.log().Infof("finish: draining for %s before shutdown", f.PreShutdownDelay)
+		time.Sleep(f.PreShutdownDelay)
+	}
+
+	var err error
+	if f.Sequential {
+		err = f.shutdownSequential()
+	} else {
+		err = f.shutdownParallel()
+	}
+
+	for _, fn := range f.afterShutdown {
+		fn()
+	}
+
+	return err
+}
+
+// shutdownKeeper shuts down a single server with its configured timeout,
+// logging the outcome, and returns an error describing a failed or timed
+// out shutdown.
+func (f *Finisher) shutdownKeeper(keeper *serverKeeper) error {
+	if keeper.preShutdown != nil {
+		if err := keeper.preShutdown(); err != nil {
+			f.log().Errorf("finish: pre-shutdown hook for %s failed: %s", keeper.name, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), keeper.timeout)
+	defer cancel()
+
+	if keeper.connCount != nil {
+		f.log().Infof("finish: shutting down %s (%d active connections) ...", keeper.name, keeper.connCount())
+	} else {
 		f.log().Infof("finish: shutting down %s ...", keeper.name)
-		err := keeper.srv.Shutdown(ctx)
-		if err != nil {
-			if err == context.DeadlineExceeded {
-				f.log().Errorf("finish: shutdown timeout for %s", keeper.name)
-			} else {
-				f.log().Errorf("finish: error while shutting down %s: %s", keeper.name, err)
-			}
+	}
+
+	err := keeper.srv.Shutdown(ctx)
+	if err != nil {
+		if err == context.DeadlineExceeded {
+			f.log().Errorf("finish: shutdown timeout for %s", keeper.name)
 		} else {
-			f.log().Infof("finish: %s closed", keeper.name)
+			f.log().Errorf("finish: error while shutting down %s: %s", keeper.name, err)
 		}
+	} else {
+		f.log().Infof("finish: %s closed", keeper.name)
+	}
+
+	if keeper.postShutdown != nil {
+		keeper.postShutdown()
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", keeper.name, err)
+	}
+	return nil
+}
+
+// phases groups the keepers by their WithShutdownOrder value, in ascending
+// order, so they can be drained phase by phase.
+func (f *Finisher) phases() [][]*serverKeeper {
+	byOrder := map[int][]*serverKeeper{}
+	for _, keeper := range f.keepers {
+		byOrder[keeper.order] = append(byOrder[keeper.order], keeper)
 	}
+
+	orders := make([]int, 0, len(byOrder))
+	for order := range byOrder {
+		orders = append(orders, order)
+	}
+	sort.Ints(orders)
+
+	phases := make([][]*serverKeeper, len(orders))
+	for i, order := range orders {
+		phases[i] = byOrder[order]
+	}
+
+	return phases
+}
+
+// shutdownSequential shuts down the keepers phase by phase (see
+// WithShutdownOrder), and within each phase one after another, in Add()
+// order.
+func (f *Finisher) shutdownSequential() error {
+	var errs []error
+	for _, phase := range f.phases() {
+		for _, keeper := range phase {
+			if err := f.shutdownKeeper(keeper); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// shutdownParallel shuts down the keepers phase by phase (see
+// WithShutdownOrder), and within each phase concurrently, each with its own
+// timeout, so the time spent per phase is bound by its slowest keeper rather
+// than the sum of all timeouts.
+func (f *Finisher) shutdownParallel() error {
+	var errs []error
+
+	for _, phase := range f.phases() {
+		var (
+			wg sync.WaitGroup
+			mu sync.Mutex
+		)
+
+		for _, keeper := range phase {
+			wg.Add(1)
+			go func(keeper *serverKeeper) {
+				defer wg.Done()
+				if err := f.shutdownKeeper(keeper); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}(keeper)
+		}
+
+		wg.Wait()
+	}
+
+	return errors.Join(errs...)
 }
 
 // Trigger the shutdown signal manually. This is probably only useful for testing.
@@ -168,6 +374,37 @@ func (f *Finisher) Trigger() {
 	f.getManSig() <- nil
 }
 
+// OnSignal registers a callback which is invoked with the signal that
+// triggered the shutdown (nil if Wait returned because of a Trigger() call),
+// before any draining or server shutdown begins. Multiple callbacks can be
+// registered by calling OnSignal more than once; they run in registration
+// order.
+func (f *Finisher) OnSignal(fn func(os.Signal)) {
+	f.onSignal = append(f.onSignal, fn)
+}
+
+// BeforeShutdown registers a callback which runs once, before any
+// configured PreShutdownDelay and before the first server's Shutdown is
+// called. Multiple callbacks can be registered by calling BeforeShutdown
+// more than once; they run in registration order.
+//
+// The canonical use case is flipping a Kubernetes readiness probe to
+// unready, so that PreShutdownDelay gives the load balancer time to notice
+// and stop sending new traffic before servers start draining.
+//
+// If a callback returns an error, it is logged but does not stop the
+// shutdown from proceeding.
+func (f *Finisher) BeforeShutdown(fn func() error) {
+	f.beforeShutdown = append(f.beforeShutdown, fn)
+}
+
+// AfterShutdown registers a callback which runs once, after every server has
+// been shut down. Multiple callbacks can be registered by calling
+// AfterShutdown more than once; they run in registration order.
+func (f *Finisher) AfterShutdown(fn func()) {
+	f.afterShutdown = append(f.afterShutdown, fn)
+}
+
 func (f *Finisher) updateNames() {
 	if len(f.keepers) == 1 && f.keepers[0].name == "" {
 		f.keepers[0].name = "server"
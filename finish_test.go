@@ -406,3 +406,209 @@ func TestCustomSignal(t *testing.T) {
 		t.Error("expected no error logs")
 	}
 }
+
+func TestParallelShutdownTiming(t *testing.T) {
+	srv1 := &testServer{wait: time.Second}
+	srv2 := &testServer{wait: 2 * time.Second}
+	srv3 := &testServer{wait: 3 * time.Second}
+
+	fin := New()
+	fin.Add(srv1, WithName("srv1"), WithTimeout(5*time.Second))
+	fin.Add(srv2, WithName("srv2"), WithTimeout(5*time.Second))
+	fin.Add(srv3, WithName("srv3"), WithTimeout(5*time.Second))
+
+	go fin.Trigger()
+
+	start := time.Now()
+	if err := fin.Wait(); err != nil {
+		t.Errorf("expected no error, got: %s", err)
+	}
+	elapsed := time.Since(start)
+
+	// total wall time should be roughly max(waits) == 3s, not the sum (6s)
+	if elapsed >= 4*time.Second {
+		t.Errorf("expected parallel shutdown, took %s", elapsed)
+	}
+
+	if !srv1.shutdown || !srv2.shutdown || !srv3.shutdown {
+		t.Error("expected all servers to be shutdown")
+	}
+}
+
+func TestSequentialOption(t *testing.T) {
+	srv1 := &testServer{wait: 200 * time.Millisecond}
+	srv2 := &testServer{wait: 200 * time.Millisecond}
+
+	fin := &Finisher{Sequential: true}
+	fin.Add(srv1, WithName("srv1"))
+	fin.Add(srv2, WithName("srv2"))
+
+	go fin.Trigger()
+
+	start := time.Now()
+	if err := fin.Wait(); err != nil {
+		t.Errorf("expected no error, got: %s", err)
+	}
+	elapsed := time.Since(start)
+
+	if elapsed < 400*time.Millisecond {
+		t.Errorf("expected sequential shutdown to take at least the sum of waits, took %s", elapsed)
+	}
+}
+
+func TestAggregateError(t *testing.T) {
+	srv1 := &testServerErr{}
+	srv2 := &testServerErr{}
+
+	fin := New()
+	fin.Add(srv1, WithName("srv1"), WithTimeout(time.Second))
+	fin.Add(srv2, WithName("srv2"), WithTimeout(time.Second))
+
+	go fin.Trigger()
+
+	err := fin.Wait()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if !errors.Is(err, errTest) {
+		t.Error("expected aggregate error to wrap errTest")
+	}
+
+	if strings.Count(err.Error(), "test error") != 2 {
+		t.Error("expected aggregate error to mention both failures")
+	}
+}
+
+func TestOnSignal(t *testing.T) {
+	srv := &testServer{}
+
+	var gotSig os.Signal
+
+	fin := New()
+	fin.Add(srv)
+	fin.OnSignal(func(sig os.Signal) {
+		gotSig = sig
+	})
+
+	go func() {
+		time.Sleep(time.Second)
+		p, err := os.FindProcess(os.Getpid())
+		if err != nil {
+			panic(err)
+		}
+		p.Signal(syscall.SIGTERM)
+	}()
+
+	fin.Wait()
+
+	if gotSig != syscall.SIGTERM {
+		t.Errorf("expected OnSignal to receive SIGTERM, got %v", gotSig)
+	}
+}
+
+func TestBeforeAndAfterShutdown(t *testing.T) {
+	srv := &testServer{}
+
+	var order []string
+
+	fin := New()
+	fin.Add(srv)
+	fin.BeforeShutdown(func() error {
+		order = append(order, "before")
+		return nil
+	})
+	fin.AfterShutdown(func() {
+		order = append(order, "after")
+	})
+
+	go fin.Trigger()
+
+	fin.Wait()
+
+	if !reflect.DeepEqual(order, []string{"before", "after"}) {
+		t.Errorf("wrong hook order: %v", order)
+	}
+}
+
+func TestPreShutdownDelay(t *testing.T) {
+	srv := &testServer{}
+	log := &logRecorder{}
+
+	fin := &Finisher{Log: log, PreShutdownDelay: 200 * time.Millisecond}
+	fin.Add(srv)
+
+	go fin.Trigger()
+
+	start := time.Now()
+	fin.Wait()
+	elapsed := time.Since(start)
+
+	if elapsed < 200*time.Millisecond {
+		t.Errorf("expected Wait to block for at least PreShutdownDelay, took %s", elapsed)
+	}
+
+	if !reflect.DeepEqual(log.infos, []string{
+		"finish: shutdown signal received",
+		"finish: draining for 200ms before shutdown",
+		"finish: shutting down server ...",
+		"finish: server closed",
+	}) {
+		t.Error("wrong log output")
+	}
+}
+
+func TestPreShutdownDelayAfterBeforeShutdownHook(t *testing.T) {
+	srv := &testServer{}
+
+	var hookAt, sleepStartAt time.Time
+
+	fin := &Finisher{PreShutdownDelay: 100 * time.Millisecond}
+	fin.Add(srv)
+	fin.BeforeShutdown(func() error {
+		hookAt = time.Now()
+		return nil
+	})
+
+	go fin.Trigger()
+
+	start := time.Now()
+	fin.Wait()
+	sleepStartAt = start
+
+	if hookAt.IsZero() {
+		t.Fatal("expected BeforeShutdown hook to run")
+	}
+
+	// the hook must run before the drain delay starts, so a readiness probe
+	// flipped inside it has the whole PreShutdownDelay window to take effect
+	// before Shutdown is called on any server
+	if hookAt.Sub(sleepStartAt) >= 50*time.Millisecond {
+		t.Errorf("expected BeforeShutdown hook to run before the drain delay, but it ran %s after Wait started", hookAt.Sub(sleepStartAt))
+	}
+}
+
+func TestPerServerHooks(t *testing.T) {
+	srv := &testServer{}
+
+	var order []string
+
+	fin := New()
+	fin.Add(srv,
+		WithPreShutdown(func() error {
+			order = append(order, "pre")
+			return nil
+		}),
+		WithPostShutdown(func() {
+			order = append(order, "post")
+		}),
+	)
+
+	go fin.Trigger()
+
+	fin.Wait()
+
+	if !reflect.DeepEqual(order, []string{"pre", "post"}) {
+		t.Errorf("wrong hook order: %v", order)
+	}
+}
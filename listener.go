@@ -0,0 +1,105 @@
+package finish
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// envListenFDs is the environment variable finish sets on the child process
+// during a restart triggered via [Finisher.Restart], carrying the number of
+// listening file descriptors which were passed through Cmd.ExtraFiles.
+const envListenFDs = "FINISH_LISTEN_FDS"
+
+// listenFDsStart is the first file descriptor number used for inherited
+// listeners, matching the systemd socket activation convention (0, 1 and 2
+// are reserved for stdin, stdout and stderr).
+const listenFDsStart = 3
+
+var (
+	listenMutex sync.Mutex
+	// listenUsed counts how many inherited file descriptors have already
+	// been claimed by a call to Listen() in this process.
+	listenUsed int
+	// listenAll keeps track, in call order, of every listener handed out by
+	// Listen() so a later Finisher.Restart() can pass them on to the child.
+	listenAll []net.Listener
+)
+
+// Listen creates a net.Listener for the given network and address.
+//
+// If the process was started as part of a restart triggered by
+// [Finisher.Restart] (detected via the FINISH_LISTEN_FDS environment
+// variable) or via systemd socket activation (detected via LISTEN_FDS and
+// LISTEN_PID), the next inherited file descriptor is used instead of opening
+// a new listener, so the new process can take over an already bound socket
+// without dropping connections.
+//
+// Listen is meant to be used in place of net.Listen when a server should
+// support zero-downtime restarts:
+// 	ln, err := finish.Listen("tcp", srv.Addr)
+// 	...
+// 	srv.Serve(ln)
+func Listen(network, addr string) (net.Listener, error) {
+	listenMutex.Lock()
+	idx := listenUsed
+	inherited := idx < inheritedListenFDs()
+	if inherited {
+		listenUsed++
+	}
+	listenMutex.Unlock()
+
+	var ln net.Listener
+	if inherited {
+		file := os.NewFile(uintptr(listenFDsStart+idx), fmt.Sprintf("finish-inherited-%d", idx))
+		fileLn, err := net.FileListener(file)
+		if err != nil {
+			return nil, fmt.Errorf("finish: failed to inherit listener %d: %w", idx, err)
+		}
+		if err := file.Close(); err != nil {
+			return nil, fmt.Errorf("finish: failed to close inherited file for listener %d: %w", idx, err)
+		}
+		ln = fileLn
+	} else {
+		newLn, err := net.Listen(network, addr)
+		if err != nil {
+			return nil, err
+		}
+		ln = newLn
+	}
+
+	listenMutex.Lock()
+	listenAll = append(listenAll, ln)
+	listenMutex.Unlock()
+
+	return ln, nil
+}
+
+// inheritedListenFDs returns how many listening file descriptors were
+// passed to this process, either by a parent finish process restarting
+// itself or by systemd socket activation. It returns 0 if neither applies.
+func inheritedListenFDs() int {
+	if s := os.Getenv(envListenFDs); s != "" {
+		if n, err := strconv.Atoi(s); err == nil {
+			return n
+		}
+	}
+
+	if pid, err := strconv.Atoi(os.Getenv("LISTEN_PID")); err == nil && pid == os.Getpid() {
+		if n, err := strconv.Atoi(os.Getenv("LISTEN_FDS")); err == nil {
+			return n
+		}
+	}
+
+	return 0
+}
+
+// listeners returns the listeners handed out by Listen() so far, in the
+// order they were created.
+func listeners() []net.Listener {
+	listenMutex.Lock()
+	defer listenMutex.Unlock()
+	return append([]net.Listener(nil), listenAll...)
+}
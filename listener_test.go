@@ -0,0 +1,60 @@
+package finish
+
+import (
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestInheritedListenFDsNone(t *testing.T) {
+	os.Unsetenv(envListenFDs)
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+
+	if n := inheritedListenFDs(); n != 0 {
+		t.Errorf("expected 0, got %d", n)
+	}
+}
+
+func TestInheritedListenFDsFinish(t *testing.T) {
+	os.Setenv(envListenFDs, "2")
+	defer os.Unsetenv(envListenFDs)
+
+	if n := inheritedListenFDs(); n != 2 {
+		t.Errorf("expected 2, got %d", n)
+	}
+}
+
+func TestInheritedListenFDsSystemd(t *testing.T) {
+	os.Unsetenv(envListenFDs)
+	os.Setenv("LISTEN_PID", "999999999")
+	os.Setenv("LISTEN_FDS", "3")
+	defer os.Unsetenv("LISTEN_PID")
+	defer os.Unsetenv("LISTEN_FDS")
+
+	if n := inheritedListenFDs(); n != 0 {
+		t.Errorf("expected 0 for a LISTEN_PID which is not us, got %d", n)
+	}
+
+	os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+
+	if n := inheritedListenFDs(); n != 3 {
+		t.Errorf("expected 3, got %d", n)
+	}
+}
+
+func TestListenFresh(t *testing.T) {
+	os.Unsetenv(envListenFDs)
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+
+	ln, err := Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer ln.Close()
+
+	if ln.Addr() == nil {
+		t.Error("expected listener to have an address")
+	}
+}
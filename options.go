@@ -25,3 +25,51 @@ func WithName(name string) Option {
 		return nil
 	}
 }
+
+// WithPreShutdown registers a callback which runs immediately before this
+// specific server's Shutdown is called.
+//
+// If the callback returns an error, it is logged but does not prevent the
+// server from being shut down.
+func WithPreShutdown(fn func() error) Option {
+	return func(keeper *serverKeeper) error {
+		keeper.preShutdown = fn
+		return nil
+	}
+}
+
+// WithPostShutdown registers a callback which runs immediately after this
+// specific server's Shutdown call returns, regardless of whether it
+// succeeded.
+func WithPostShutdown(fn func()) Option {
+	return func(keeper *serverKeeper) error {
+		keeper.postShutdown = fn
+		return nil
+	}
+}
+
+// WithConnCount associates a live connection counter, as returned by
+// [CountingListener], with the server to be registered via [Finisher.Add].
+// When set, the shutdown log reports how many connections were still in
+// flight, e.g. “finish: shutting down server (34 active connections) ...”.
+func WithConnCount(counter func() int) Option {
+	return func(keeper *serverKeeper) error {
+		keeper.connCount = counter
+		return nil
+	}
+}
+
+// WithShutdownOrder controls when, relative to other registered servers,
+// this server is drained. Servers are shut down phase by phase in ascending
+// order; servers sharing the same order (the default, 0) are drained
+// together as one phase, concurrently unless Finisher.Sequential is set.
+//
+// This allows draining e.g. HTTP servers first at order 0, then Kafka
+// consumers at order 10, then DB pools at order 20, so upstream resources
+// outlive anything that might still be using them.
+func WithShutdownOrder(order int) Option {
+	return func(keeper *serverKeeper) error {
+		keeper.order = order
+		return nil
+	}
+}
@@ -0,0 +1,99 @@
+package finish
+
+import (
+	"context"
+	"io"
+)
+
+// funcServer adapts a plain shutdown function into a Server, as registered
+// by AddFunc.
+type funcServer func(ctx context.Context) error
+
+func (fn funcServer) Shutdown(ctx context.Context) error {
+	return fn(ctx)
+}
+
+// AddFunc registers an arbitrary shutdown function for graceful shutdown, as
+// an alternative to Add() for resources which aren't already a Server, e.g.
+// a database pool, a Kafka consumer, or an OpenTelemetry tracer provider.
+//
+// Options can be passed the same way as for Add():
+// 	fin.AddFunc("db pool", func(ctx context.Context) error {
+// 		return dbPool.Close()
+// 	})
+func (f *Finisher) AddFunc(name string, shutdown func(ctx context.Context) error, opts ...Option) {
+	f.Add(funcServer(shutdown), append(opts, WithName(name))...)
+}
+
+// AddCloser registers an io.Closer for graceful shutdown, as an alternative
+// to Add() for resources which only expose a context-less Close() error.
+//
+// Close is run in its own goroutine; if the keeper's timeout elapses first,
+// Shutdown returns ctx.Err() without waiting for Close to return.
+func (f *Finisher) AddCloser(name string, c io.Closer, opts ...Option) {
+	f.AddFunc(name, func(ctx context.Context) error {
+		done := make(chan error, 1)
+		go func() {
+			done <- c.Close()
+		}()
+
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}, opts...)
+}
+
+// GRPCStopper is implemented by *grpc.Server and similar types whose
+// graceful shutdown method, unlike Server.Shutdown, doesn't accept a
+// context.
+type GRPCStopper interface {
+	GracefulStop()
+}
+
+// grpcForceStopper is implemented by *grpc.Server (and similar types) via
+// Stop(), which forcefully closes all connections and makes a concurrent
+// GracefulStop() call return immediately. GRPCServer uses it to actually
+// honor the keeper's timeout instead of leaving GracefulStop's goroutine
+// running forever once ctx expires.
+type grpcForceStopper interface {
+	Stop()
+}
+
+// GRPCServer adapts a GRPCStopper, such as a *grpc.Server, into a Server
+// accepted by Add(), so gRPC servers can be drained in the same shutdown
+// pipeline as everything else:
+// 	fin.Add(finish.GRPCServer(grpcSrv), finish.WithName("grpc server"))
+//
+// GracefulStop runs in its own goroutine; if the keeper's timeout elapses
+// first, Shutdown calls Stop() on s (if it implements the forceful Stop()
+// method, as *grpc.Server does) to make GracefulStop return, then returns
+// ctx.Err(). Without a Stop() method, GracefulStop's goroutine is left
+// running until the server drains on its own.
+func GRPCServer(s GRPCStopper) Server {
+	return &grpcServer{s: s}
+}
+
+type grpcServer struct {
+	s GRPCStopper
+}
+
+func (g *grpcServer) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		g.s.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		if fs, ok := g.s.(grpcForceStopper); ok {
+			fs.Stop()
+		}
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,182 @@
+package finish
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testCloser struct {
+	closed bool
+}
+
+func (c *testCloser) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestAddFunc(t *testing.T) {
+	var called bool
+
+	fin := New()
+	fin.AddFunc("my func", func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	go fin.Trigger()
+
+	fin.Wait()
+
+	if !called {
+		t.Error("expected shutdown function to be called")
+	}
+
+	if fin.keepers[0].name != "my func" {
+		t.Error("expected name to be set")
+	}
+}
+
+func TestAddCloser(t *testing.T) {
+	closer := &testCloser{}
+
+	fin := New()
+	fin.AddCloser("my closer", closer)
+
+	go fin.Trigger()
+
+	fin.Wait()
+
+	if !closer.closed {
+		t.Error("expected closer to be closed")
+	}
+}
+
+func TestAddCloserTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	closer := &blockingCloser{block: block}
+
+	fin := New()
+	fin.AddCloser("slow closer", closer, WithTimeout(100*time.Millisecond))
+
+	go fin.Trigger()
+
+	err := fin.Wait()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded error, got: %s", err)
+	}
+}
+
+type blockingCloser struct {
+	block chan struct{}
+}
+
+func (c *blockingCloser) Close() error {
+	<-c.block
+	return nil
+}
+
+type testGRPCStopper struct {
+	stopped bool
+}
+
+func (s *testGRPCStopper) GracefulStop() {
+	s.stopped = true
+}
+
+func TestGRPCServer(t *testing.T) {
+	stopper := &testGRPCStopper{}
+
+	fin := New()
+	fin.Add(GRPCServer(stopper), WithName("grpc server"))
+
+	go fin.Trigger()
+
+	fin.Wait()
+
+	if !stopper.stopped {
+		t.Error("expected GracefulStop to be called")
+	}
+}
+
+type testGRPCForceStopper struct {
+	stopCh       chan struct{}
+	gracefulDone bool
+	forceStopped bool
+}
+
+func (s *testGRPCForceStopper) GracefulStop() {
+	// a real *grpc.Server's GracefulStop blocks until all in-flight RPCs
+	// finish on their own, or until a concurrent Stop() forces it to return
+	<-s.stopCh
+	s.gracefulDone = true
+}
+
+func (s *testGRPCForceStopper) Stop() {
+	s.forceStopped = true
+	close(s.stopCh)
+}
+
+func TestGRPCServerForceStopOnTimeout(t *testing.T) {
+	stopper := &testGRPCForceStopper{stopCh: make(chan struct{})}
+
+	srv := GRPCServer(stopper)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := srv.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded error, got: %s", err)
+	}
+
+	if !stopper.forceStopped {
+		t.Error("expected Stop to be called to force the stuck GracefulStop to return")
+	}
+}
+
+func TestShutdownOrder(t *testing.T) {
+	var order []string
+	var mu mutexRecorder
+
+	srv1 := &testServer{}
+	srv2 := &testServer{}
+	srv3 := &testServer{}
+
+	fin := New()
+	fin.Add(srv1, WithName("db"), WithShutdownOrder(20), WithPostShutdown(func() {
+		mu.record(&order, "db")
+	}))
+	fin.Add(srv2, WithName("http"), WithShutdownOrder(0), WithPostShutdown(func() {
+		mu.record(&order, "http")
+	}))
+	fin.Add(srv3, WithName("kafka"), WithShutdownOrder(10), WithPostShutdown(func() {
+		mu.record(&order, "kafka")
+	}))
+
+	go fin.Trigger()
+
+	fin.Wait()
+
+	if !reflect.DeepEqual(order, []string{"http", "kafka", "db"}) {
+		t.Errorf("expected phases to be drained in ascending order, got: %v", order)
+	}
+}
+
+// mutexRecorder serializes appends from concurrently shut down keepers
+// within the same phase, so the test can assert on phase order without
+// caring about order within a phase.
+type mutexRecorder struct {
+	mu sync.Mutex
+}
+
+func (m *mutexRecorder) record(order *[]string, name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	*order = append(*order, name)
+}
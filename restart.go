@@ -0,0 +1,152 @@
+package finish
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultRestartSignal (SIGHUP) is not applied automatically: restarts stay
+// disabled while Finisher.RestartSignal is nil. It is provided as a
+// convenient default to opt into:
+// 	fin.RestartSignal = finish.DefaultRestartSignal
+var DefaultRestartSignal os.Signal = syscall.SIGHUP
+
+// DefaultRestartHammerTime is used if Finisher.RestartHammerTime is not set.
+const DefaultRestartHammerTime = 10 * time.Second
+
+// readySignal is sent by a restarted child process to its parent, via
+// Process.Signal, to announce that it is ready to accept connections on the
+// inherited listeners.
+const readySignal = syscall.SIGUSR1
+
+// filer is implemented by net.Listener types (e.g. *net.TCPListener and
+// *net.UnixListener) which can expose their underlying file descriptor, as
+// required to pass a listener to a child process via Cmd.ExtraFiles.
+type filer interface {
+	File() (*os.File, error)
+}
+
+// newRestartCmd builds the command used to re-exec the running binary
+// during a restart. It is a variable so tests can substitute a short-lived
+// fake child process.
+var newRestartCmd = func() *exec.Cmd {
+	return exec.Command(os.Args[0], os.Args[1:]...)
+}
+
+func (f *Finisher) restartHammerTime() time.Duration {
+	if f.RestartHammerTime != 0 {
+		return f.RestartHammerTime
+	}
+	return DefaultRestartHammerTime
+}
+
+// Restart triggers a zero-downtime restart manually, as an alternative to
+// sending Finisher.RestartSignal to the process.
+func (f *Finisher) Restart() {
+	f.getManRestartSig() <- nil
+}
+
+func (f *Finisher) getManRestartSig() chan interface{} {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.manRestartSig == nil {
+		f.manRestartSig = make(chan interface{}, 1)
+	}
+	return f.manRestartSig
+}
+
+// doRestart re-execs the running binary, passing on every listener created
+// via Listen(), and waits for the child to report readiness. It returns
+// true if the child came up successfully, in which case the caller should
+// proceed to drain and shut down the current process' servers. It returns
+// false if the restart failed or timed out, in which case the current
+// process should keep running as if nothing happened.
+func (f *Finisher) doRestart() bool {
+	lns := listeners()
+
+	files := make([]*os.File, len(lns))
+	for i, ln := range lns {
+		fl, ok := ln.(filer)
+		if !ok {
+			f.log().Errorf("finish: restart aborted: listener #%d (%T) does not support being inherited", i, ln)
+			return false
+		}
+		file, err := fl.File()
+		if err != nil {
+			f.log().Errorf("finish: restart aborted: %s", err)
+			return false
+		}
+		files[i] = file
+	}
+
+	readyCh := make(chan os.Signal, 1)
+	signal.Notify(readyCh, readySignal)
+	defer signal.Stop(readyCh)
+
+	cmd := newRestartCmd()
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", envListenFDs, len(files)))
+	cmd.ExtraFiles = files
+
+	err := cmd.Start()
+
+	// the dup'd fds in files were either passed on to the child (which has
+	// its own copies now) or never will be, so the parent's copies must be
+	// closed either way to avoid leaking them on every restart attempt
+	for _, file := range files {
+		if err := file.Close(); err != nil {
+			f.log().Errorf("finish: failed to close handed-off listener file: %s", err)
+		}
+	}
+
+	if err != nil {
+		f.log().Errorf("finish: restart aborted: failed to start new process: %s", err)
+		return false
+	}
+
+	// reap the child whenever it exits, whether that's because it took over
+	// successfully and eventually shuts down on its own, or because it gets
+	// killed below for not becoming ready in time; without this the child
+	// lingers as a zombie for the remaining lifetime of this process
+	go cmd.Wait()
+
+	f.log().Infof("finish: new process started with pid %d, waiting for it to become ready", cmd.Process.Pid)
+
+	select {
+	case <-readyCh:
+		f.log().Infof("finish: new process is ready")
+		return true
+	case <-time.After(f.restartHammerTime()):
+		f.log().Errorf("finish: restart aborted: new process did not become ready within %s", f.restartHammerTime())
+		if err := cmd.Process.Kill(); err != nil {
+			f.log().Errorf("finish: failed to kill unresponsive new process: %s", err)
+		}
+		return false
+	}
+}
+
+// Ready signals a parent process, which started the calling process as part
+// of a restart triggered via Finisher.Restart, that it is ready to accept
+// connections, so the parent can start draining and shutting down its own
+// servers.
+//
+// Calling Ready in a process which was not started as part of a restart
+// (i.e. FINISH_LISTEN_FDS is not set) is a no-op.
+func Ready() error {
+	if os.Getenv(envListenFDs) == "" {
+		return nil
+	}
+
+	p, err := os.FindProcess(os.Getppid())
+	if err != nil {
+		return err
+	}
+
+	return p.Signal(readySignal)
+}
@@ -0,0 +1,189 @@
+package finish
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// restartChildHelperEnv, when set, tells TestMain to run this test binary as
+// the child helper used by TestDoRestartSuccessHandsOffListener instead of
+// running the normal test suite, following the same re-exec-self trick used
+// by the os/exec package's own tests.
+const restartChildHelperEnv = "FINISH_TEST_RESTART_CHILD"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(restartChildHelperEnv) != "" {
+		runRestartTestChild()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// runRestartTestChild stands in for a real restarted process: it takes over
+// the inherited listener, signals readiness to its parent, then proves the
+// handed-off fd actually works by accepting one connection and echoing a
+// known line back on it.
+func runRestartTestChild() {
+	ln, err := Listen("tcp", "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "child: Listen failed: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := Ready(); err != nil {
+		fmt.Fprintf(os.Stderr, "child: Ready failed: %s\n", err)
+		os.Exit(1)
+	}
+
+	conn, err := ln.Accept()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "child: Accept failed: %s\n", err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	if _, err := fmt.Fprintln(conn, "hello from restarted child"); err != nil {
+		fmt.Fprintf(os.Stderr, "child: write failed: %s\n", err)
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+func TestRestartHammerTimeDefault(t *testing.T) {
+	fin := New()
+
+	if fin.restartHammerTime() != DefaultRestartHammerTime {
+		t.Error("expected default restart hammer time")
+	}
+}
+
+func TestRestartHammerTimeOverride(t *testing.T) {
+	fin := &Finisher{RestartHammerTime: 30 * time.Second}
+
+	if fin.restartHammerTime() != 30*time.Second {
+		t.Error("expected overridden restart hammer time")
+	}
+}
+
+func TestReadyNoop(t *testing.T) {
+	os.Unsetenv(envListenFDs)
+
+	if err := Ready(); err != nil {
+		t.Errorf("expected Ready() to be a no-op, got: %s", err)
+	}
+}
+
+func TestDoRestartReapsKilledChild(t *testing.T) {
+	// doRestart() picks up whatever Listen() accumulated across the whole
+	// test binary so far; isolate this test from that shared state.
+	listenMutex.Lock()
+	savedListeners := listenAll
+	listenAll = nil
+	listenMutex.Unlock()
+	defer func() {
+		listenMutex.Lock()
+		listenAll = savedListeners
+		listenMutex.Unlock()
+	}()
+
+	var createdCmd *exec.Cmd
+
+	origNewRestartCmd := newRestartCmd
+	newRestartCmd = func() *exec.Cmd {
+		// a fake child which never signals readiness, forcing doRestart to
+		// hit RestartHammerTime and kill it
+		createdCmd = exec.Command("sleep", "5")
+		return createdCmd
+	}
+	defer func() { newRestartCmd = origNewRestartCmd }()
+
+	fin := &Finisher{RestartHammerTime: 50 * time.Millisecond}
+
+	if fin.doRestart() {
+		t.Fatal("expected doRestart to fail since the fake child never signals readiness")
+	}
+
+	if createdCmd == nil || createdCmd.Process == nil {
+		t.Fatal("expected the fake child to have been started")
+	}
+	pid := createdCmd.Process.Pid
+
+	// the reaping goroutine runs cmd.Wait() concurrently with the Kill()
+	// call above, so give it a moment to actually reap the process
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		err := syscall.Kill(pid, 0)
+		if err == syscall.ESRCH {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected child process %d to be reaped, but it is still present (likely a zombie)", pid)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDoRestartSuccessHandsOffListener(t *testing.T) {
+	// doRestart() picks up whatever Listen() accumulated across the whole
+	// test binary so far; isolate this test from that shared state.
+	listenMutex.Lock()
+	savedListeners := listenAll
+	savedUsed := listenUsed
+	listenAll = nil
+	listenUsed = 0
+	listenMutex.Unlock()
+	defer func() {
+		listenMutex.Lock()
+		listenAll = savedListeners
+		listenUsed = savedUsed
+		listenMutex.Unlock()
+	}()
+
+	ln, err := Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	addr := ln.Addr().String()
+
+	// doRestart() always overwrites cmd.Env with FINISH_LISTEN_FDS right
+	// after newRestartCmd returns, so restartChildHelperEnv has to be set on
+	// this process' own environment to reach the child via os.Environ().
+	os.Setenv(restartChildHelperEnv, "1")
+	defer os.Unsetenv(restartChildHelperEnv)
+
+	origNewRestartCmd := newRestartCmd
+	newRestartCmd = func() *exec.Cmd {
+		return exec.Command(os.Args[0], "-test.run=^TestMain$")
+	}
+	defer func() { newRestartCmd = origNewRestartCmd }()
+
+	fin := &Finisher{RestartHammerTime: 10 * time.Second}
+
+	if !fin.doRestart() {
+		t.Fatal("expected doRestart to succeed once the child signals readiness")
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
+	if err != nil {
+		t.Fatalf("failed to dial the handed-off listener: %s", err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		t.Fatalf("failed to read from the restarted child: %s", err)
+	}
+
+	const want = "hello from restarted child\n"
+	if line != want {
+		t.Errorf("expected %q from the restarted child, got %q", want, line)
+	}
+}